@@ -0,0 +1,420 @@
+// Copyright 2017 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gitlab
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/jenkins-x/go-scm/scm"
+)
+
+// webhookService implements scm.WebhookService for GitLab.
+type webhookService struct {
+	client *wrapper
+}
+
+// gitlabProject is the "project" object GitLab embeds in every
+// project-scoped webhook payload.
+type gitlabProject struct {
+	ID                int    `json:"id"`
+	Name              string `json:"name"`
+	Namespace         string `json:"namespace"`
+	PathWithNamespace string `json:"path_with_namespace"`
+	DefaultBranch     string `json:"default_branch"`
+	WebURL            string `json:"web_url"`
+	Visibility        string `json:"visibility"`
+}
+
+func convertProject(in *gitlabProject) scm.Repository {
+	return scm.Repository{
+		ID:        strconv.Itoa(in.ID),
+		Namespace: in.Namespace,
+		Name:      in.Name,
+		FullName:  in.PathWithNamespace,
+		Branch:    in.DefaultBranch,
+		Link:      in.WebURL,
+		Private:   in.Visibility != "public",
+	}
+}
+
+// gitlabEventUser is the "user" object GitLab embeds in most
+// project-scoped webhook payloads.
+type gitlabEventUser struct {
+	Name     string `json:"name"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+func convertEventUser(in *gitlabEventUser) scm.User {
+	return scm.User{
+		Login: in.Username,
+		Name:  in.Name,
+		Email: in.Email,
+	}
+}
+
+// convertAction maps the action string GitLab includes on
+// object_attributes to this package's Action enum.
+func convertAction(action string) scm.Action {
+	switch action {
+	case "open":
+		return scm.ActionOpen
+	case "close":
+		return scm.ActionClose
+	case "reopen":
+		return scm.ActionReopen
+	case "update":
+		return scm.ActionUpdate
+	case "create":
+		return scm.ActionCreate
+	default:
+		return scm.Action(0)
+	}
+}
+
+// systemHookEnvelope is a superset of the fields carried on every
+// GitLab System Hook payload. GitLab does not tag the payload
+// with a discriminating "type" field the way it tags project
+// webhooks; event_name is the only reliable discriminator, so the
+// envelope is decoded once and narrowed by convertSystemHook.
+type systemHookEnvelope struct {
+	EventName         string `json:"event_name"`
+	ProjectID         int    `json:"project_id"`
+	Name              string `json:"name"`
+	Path              string `json:"path"`
+	PathWithNamespace string `json:"path_with_namespace"`
+	OwnerName         string `json:"owner_name"`
+	OwnerEmail        string `json:"owner_email"`
+	UserID            int    `json:"user_id"`
+	UserEmail         string `json:"email"`
+	Username          string `json:"username"`
+	GroupID           int    `json:"group_id"`
+	GroupName         string `json:"group_name"`
+	GroupPath         string `json:"group_path"`
+	GroupAccess       string `json:"group_access"`
+	ID                int    `json:"id"`
+	Key               string `json:"key"`
+}
+
+// convertSystemHook maps a decoded envelope to the concrete
+// scm.SystemHook its event_name identifies.
+func convertSystemHook(in *systemHookEnvelope) (scm.SystemHook, error) {
+	switch in.EventName {
+	case "project_create", "project_destroy", "project_rename", "project_transfer":
+		return &scm.ProjectSystemHook{
+			Event:             in.EventName,
+			ProjectID:         in.ProjectID,
+			Name:              in.Name,
+			Path:              in.Path,
+			PathWithNamespace: in.PathWithNamespace,
+			OwnerName:         in.OwnerName,
+			OwnerEmail:        in.OwnerEmail,
+		}, nil
+	case "user_create", "user_destroy":
+		return &scm.UserSystemHook{
+			Event:    in.EventName,
+			UserID:   in.UserID,
+			Name:     in.Name,
+			Email:    in.UserEmail,
+			Username: in.Username,
+		}, nil
+	case "group_create", "group_destroy":
+		return &scm.GroupSystemHook{
+			Event:   in.EventName,
+			GroupID: in.GroupID,
+			Name:    in.GroupName,
+			Path:    in.GroupPath,
+		}, nil
+	case "user_add_to_team", "user_remove_from_team":
+		return &scm.TeamMemberSystemHook{
+			Event:        in.EventName,
+			UserID:       in.UserID,
+			UserUsername: in.Username,
+			GroupID:      in.GroupID,
+			GroupName:    in.GroupName,
+			GroupAccess:  in.GroupAccess,
+		}, nil
+	case "key_create", "key_destroy":
+		return &scm.SSHKeySystemHook{
+			Event:    in.EventName,
+			ID:       in.ID,
+			Username: in.Username,
+			Key:      in.Key,
+		}, nil
+	default:
+		return nil, fmt.Errorf("gitlab: unknown system hook event %q", in.EventName)
+	}
+}
+
+// zeroSHA is the all-zero git object ID GitLab sends as Before on
+// a branch create, or as After on a branch delete.
+const zeroSHA = "0000000000000000000000000000000000000000"
+
+// pushHookPayload covers both "Push Hook" and "Tag Push Hook",
+// which share the same shape; Commits is empty for tag pushes.
+type pushHookPayload struct {
+	Before       string        `json:"before"`
+	After        string        `json:"after"`
+	Ref          string        `json:"ref"`
+	UserName     string        `json:"user_name"`
+	UserUsername string        `json:"user_username"`
+	UserEmail    string        `json:"user_email"`
+	Project      gitlabProject `json:"project"`
+	Commits      []struct {
+		ID       string   `json:"id"`
+		Message  string   `json:"message"`
+		URL      string   `json:"url"`
+		Added    []string `json:"added"`
+		Modified []string `json:"modified"`
+		Removed  []string `json:"removed"`
+	} `json:"commits"`
+}
+
+func convertPushHook(in *pushHookPayload) *scm.PushHook {
+	var commits []scm.PushCommit
+	for _, c := range in.Commits {
+		commits = append(commits, scm.PushCommit{
+			ID:       c.ID,
+			Message:  c.Message,
+			Added:    c.Added,
+			Modified: c.Modified,
+			Removed:  c.Removed,
+		})
+	}
+	return &scm.PushHook{
+		Ref:     in.Ref,
+		Repo:    convertProject(&in.Project),
+		Before:  in.Before,
+		After:   in.After,
+		Created: in.Before == zeroSHA,
+		Deleted: in.After == zeroSHA,
+		Commits: commits,
+		Sender: scm.User{
+			Login: in.UserUsername,
+			Name:  in.UserName,
+			Email: in.UserEmail,
+		},
+	}
+}
+
+type issueHookPayload struct {
+	User             gitlabEventUser `json:"user"`
+	Project          gitlabProject   `json:"project"`
+	ObjectAttributes struct {
+		IID         int    `json:"iid"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Action      string `json:"action"`
+		URL         string `json:"url"`
+	} `json:"object_attributes"`
+}
+
+func convertIssueHook(in *issueHookPayload) *scm.IssueHook {
+	return &scm.IssueHook{
+		Action: convertAction(in.ObjectAttributes.Action),
+		Repo:   convertProject(&in.Project),
+		Issue: scm.Issue{
+			Number: in.ObjectAttributes.IID,
+			Title:  in.ObjectAttributes.Title,
+			Body:   in.ObjectAttributes.Description,
+			Link:   in.ObjectAttributes.URL,
+		},
+		Sender: convertEventUser(&in.User),
+	}
+}
+
+// mergeRequestHookPayload is GitLab's closest equivalent of
+// GitHub's pull_request event.
+type mergeRequestHookPayload struct {
+	User             gitlabEventUser `json:"user"`
+	Project          gitlabProject   `json:"project"`
+	ObjectAttributes struct {
+		IID         int    `json:"iid"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Action      string `json:"action"`
+		URL         string `json:"url"`
+	} `json:"object_attributes"`
+}
+
+func convertMergeRequestHook(in *mergeRequestHookPayload) *scm.PullRequestHook {
+	return &scm.PullRequestHook{
+		Action: convertAction(in.ObjectAttributes.Action),
+		Repo:   convertProject(&in.Project),
+		PullRequest: scm.PullRequest{
+			Number: in.ObjectAttributes.IID,
+			Title:  in.ObjectAttributes.Title,
+			Body:   in.ObjectAttributes.Description,
+			Link:   in.ObjectAttributes.URL,
+		},
+		Sender: convertEventUser(&in.User),
+	}
+}
+
+// terminalPipelineStatus holds the GitLab pipeline statuses that
+// represent a finished run; WorkflowRunHook.Conclusion is only
+// meaningful once a run has reached one of them. GitLab, unlike
+// GitHub, doesn't distinguish a separate in-progress "status" from
+// a finished "conclusion", so Status carries GitLab's status
+// verbatim and Conclusion is derived from it.
+var terminalPipelineStatus = map[string]bool{
+	"success":  true,
+	"failed":   true,
+	"canceled": true,
+	"skipped":  true,
+}
+
+// pipelineHookPayload maps GitLab's Pipeline Hook, its closest
+// equivalent of GitHub's workflow_run event, onto
+// scm.WorkflowRunHook.
+type pipelineHookPayload struct {
+	ObjectAttributes struct {
+		ID     int64  `json:"id"`
+		Ref    string `json:"ref"`
+		Sha    string `json:"sha"`
+		Status string `json:"status"`
+	} `json:"object_attributes"`
+	Project gitlabProject   `json:"project"`
+	User    gitlabEventUser `json:"user"`
+}
+
+func convertPipelineHook(in *pipelineHookPayload) *scm.WorkflowRunHook {
+	hook := &scm.WorkflowRunHook{
+		Workflow:   "pipeline",
+		RunID:      in.ObjectAttributes.ID,
+		Status:     in.ObjectAttributes.Status,
+		HeadBranch: in.ObjectAttributes.Ref,
+		HeadSHA:    in.ObjectAttributes.Sha,
+		Repo:       convertProject(&in.Project),
+		Sender:     convertEventUser(&in.User),
+	}
+	if terminalPipelineStatus[in.ObjectAttributes.Status] {
+		hook.Conclusion = in.ObjectAttributes.Status
+	}
+	return hook
+}
+
+// releaseHookPayload maps GitLab's Release Hook onto
+// scm.ReleaseHook. GitLab has no equivalent of GitHub's package
+// event for its package registry, so there is no GitLab mapping
+// for scm.PackageHook.
+type releaseHookPayload struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Tag         string        `json:"tag"`
+	URL         string        `json:"url"`
+	Action      string        `json:"action"`
+	Project     gitlabProject `json:"project"`
+}
+
+func convertReleaseHook(in *releaseHookPayload) *scm.ReleaseHook {
+	return &scm.ReleaseHook{
+		Action: convertAction(in.Action),
+		Release: scm.Release{
+			Tag:     in.Tag,
+			Name:    in.Name,
+			Body:    in.Description,
+			HTMLURL: in.URL,
+		},
+		Repo: convertProject(&in.Project),
+	}
+}
+
+// Parse returns the parsed repository webhook payload. GitLab
+// identifies the payload shape with the X-Gitlab-Event header
+// rather than tagging the body itself. A value of "System Hook"
+// identifies an instance- or group-level event rather than a
+// project webhook; Parse decodes those into a scm.SystemHook
+// instead, returned behind the scm.Webhook interface so callers
+// that only care about project webhooks are unaffected, while
+// callers that care should type-assert to scm.SystemHook and
+// check Kind.
+func (s *webhookService) Parse(req *http.Request, fn scm.SecretFunc) (scm.Webhook, error) {
+	data, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	event := req.Header.Get("X-Gitlab-Event")
+	if event == "System Hook" {
+		envelope := new(systemHookEnvelope)
+		if err := json.Unmarshal(data, envelope); err != nil {
+			return nil, err
+		}
+		hook, err := convertSystemHook(envelope)
+		if err != nil {
+			return nil, err
+		}
+		if err := validateToken(req, hook, fn); err != nil {
+			return nil, err
+		}
+		return hook, nil
+	}
+
+	var hook scm.Webhook
+	switch event {
+	case "Push Hook", "Tag Push Hook":
+		in := new(pushHookPayload)
+		if err := json.Unmarshal(data, in); err != nil {
+			return nil, err
+		}
+		hook = convertPushHook(in)
+	case "Issue Hook":
+		in := new(issueHookPayload)
+		if err := json.Unmarshal(data, in); err != nil {
+			return nil, err
+		}
+		hook = convertIssueHook(in)
+	case "Merge Request Hook":
+		in := new(mergeRequestHookPayload)
+		if err := json.Unmarshal(data, in); err != nil {
+			return nil, err
+		}
+		hook = convertMergeRequestHook(in)
+	case "Pipeline Hook":
+		in := new(pipelineHookPayload)
+		if err := json.Unmarshal(data, in); err != nil {
+			return nil, err
+		}
+		hook = convertPipelineHook(in)
+	case "Release Hook":
+		in := new(releaseHookPayload)
+		if err := json.Unmarshal(data, in); err != nil {
+			return nil, err
+		}
+		hook = convertReleaseHook(in)
+	default:
+		return nil, fmt.Errorf("gitlab: unsupported X-Gitlab-Event %q", event)
+	}
+
+	if err := validateToken(req, hook, fn); err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+// validateToken checks the X-Gitlab-Token header against the
+// secret fn returns for hook. GitLab signs both System Hooks and
+// project webhooks by echoing the configured secret verbatim
+// rather than an HMAC of the body, so the header is compared
+// directly.
+func validateToken(req *http.Request, hook scm.Webhook, fn scm.SecretFunc) error {
+	secret, err := fn(hook)
+	if err != nil {
+		return err
+	}
+	if secret == "" {
+		return nil
+	}
+	if subtle.ConstantTimeCompare([]byte(req.Header.Get("X-Gitlab-Token")), []byte(secret)) != 1 {
+		return scm.ErrSignatureInvalid
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+// Copyright 2017 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gitlab
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x/go-scm/scm"
+)
+
+// projectCreateSystemHookPayload is GitLab's own documented
+// example System Hook payload for a project_create event.
+const projectCreateSystemHookPayload = `{
+  "created_at": "2012-07-21T07:30:54Z",
+  "updated_at": "2012-07-21T07:38:22Z",
+  "event_name": "project_create",
+  "name": "StoreCloud",
+  "owner_email": "johnsmith@example.com",
+  "owner_name": "John Smith",
+  "path": "storecloud",
+  "path_with_namespace": "jsmith/storecloud",
+  "project_id": 74,
+  "project_visibility": "private"
+}`
+
+func TestWebhookServiceParseSystemHook(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(projectCreateSystemHookPayload))
+	req.Header.Set("X-Gitlab-Event", "System Hook")
+
+	s := new(webhookService)
+	hook, err := s.Parse(req, func(scm.Webhook) (string, error) { return "", nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, ok := hook.(*scm.ProjectSystemHook)
+	if !ok {
+		t.Fatalf("Parse returned %T, want *scm.ProjectSystemHook", hook)
+	}
+
+	want := &scm.ProjectSystemHook{
+		Event:             "project_create",
+		ProjectID:         74,
+		Name:              "StoreCloud",
+		Path:              "storecloud",
+		PathWithNamespace: "jsmith/storecloud",
+		OwnerName:         "John Smith",
+		OwnerEmail:        "johnsmith@example.com",
+	}
+	if *got != *want {
+		t.Errorf("Parse(System Hook) = %#v, want %#v", got, want)
+	}
+}
+
+func TestWebhookServiceParseSystemHookTokenMismatch(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(projectCreateSystemHookPayload))
+	req.Header.Set("X-Gitlab-Event", "System Hook")
+	req.Header.Set("X-Gitlab-Token", "wrong")
+
+	s := new(webhookService)
+	_, err := s.Parse(req, func(scm.Webhook) (string, error) { return "secret", nil })
+	if err != scm.ErrSignatureInvalid {
+		t.Errorf("Parse error = %v, want scm.ErrSignatureInvalid", err)
+	}
+}
+
+func TestWebhookServiceParseUnknownEvent(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+	req.Header.Set("X-Gitlab-Event", "Confidential Note Hook")
+
+	s := new(webhookService)
+	if _, err := s.Parse(req, func(scm.Webhook) (string, error) { return "", nil }); err == nil {
+		t.Error("Parse should have returned an error for an unsupported event header")
+	}
+}
+
+// pushHookPayloadExample is GitLab's own documented example
+// payload for a Push Hook.
+const pushHookPayloadExample = `{
+  "object_kind": "push",
+  "before": "95790bf891e76fee5e1747ab589903a6a1f80f22",
+  "after": "da1560886d4f094c3e6c9ef40349f7d38b5d27d7",
+  "ref": "refs/heads/master",
+  "user_name": "John Smith",
+  "user_username": "jsmith",
+  "user_email": "john@example.com",
+  "project_id": 15,
+  "project": {
+    "id": 15,
+    "name": "Diaspora",
+    "namespace": "Mike",
+    "path_with_namespace": "mike/diaspora",
+    "default_branch": "master",
+    "web_url": "http://example.com/mike/diaspora",
+    "visibility": "private"
+  },
+  "commits": [
+    {
+      "id": "b6568db1bc1dcd7f8b4d5a946b0b91f9dacd7327",
+      "message": "Update Catalan translation to e38cb41.",
+      "url": "http://example.com/mike/diaspora/commit/b6568db1bc1dcd7f8b4d5a946b0b91f9dacd7327",
+      "added": ["CHANGELOG"],
+      "modified": ["app/controller/application.rb"],
+      "removed": []
+    }
+  ],
+  "total_commits_count": 1
+}`
+
+func TestWebhookServiceParsePushHook(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(pushHookPayloadExample))
+	req.Header.Set("X-Gitlab-Event", "Push Hook")
+
+	s := new(webhookService)
+	hook, err := s.Parse(req, func(scm.Webhook) (string, error) { return "", nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, ok := hook.(*scm.PushHook)
+	if !ok {
+		t.Fatalf("Parse returned %T, want *scm.PushHook", hook)
+	}
+	if got.Ref != "refs/heads/master" {
+		t.Errorf("Ref = %q, want refs/heads/master", got.Ref)
+	}
+	if got.Repo.FullName != "mike/diaspora" {
+		t.Errorf("Repo.FullName = %q, want mike/diaspora", got.Repo.FullName)
+	}
+	if len(got.Commits) != 1 || got.Commits[0].ID != "b6568db1bc1dcd7f8b4d5a946b0b91f9dacd7327" {
+		t.Errorf("Commits = %#v, want a single decoded commit", got.Commits)
+	}
+	if got.Created || got.Deleted {
+		t.Errorf("Created = %v, Deleted = %v, want both false for an ordinary push", got.Created, got.Deleted)
+	}
+}
+
+// pipelineHookPayloadExample is GitLab's own documented example
+// payload for a Pipeline Hook, trimmed to the fields Parse reads.
+const pipelineHookPayloadExample = `{
+  "object_kind": "pipeline",
+  "object_attributes": {
+    "id": 31,
+    "ref": "master",
+    "sha": "bcbb5ec396a2c0f828686f14fac9b80b780504f2",
+    "status": "success"
+  },
+  "user": {"name": "Administrator", "username": "root", "email": "admin@example.com"},
+  "project": {"id": 1, "name": "Gitlab Test", "path_with_namespace": "gitlab-org/gitlab-test"}
+}`
+
+func TestWebhookServiceParsePipelineHook(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(pipelineHookPayloadExample))
+	req.Header.Set("X-Gitlab-Event", "Pipeline Hook")
+
+	s := new(webhookService)
+	hook, err := s.Parse(req, func(scm.Webhook) (string, error) { return "", nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, ok := hook.(*scm.WorkflowRunHook)
+	if !ok {
+		t.Fatalf("Parse returned %T, want *scm.WorkflowRunHook", hook)
+	}
+	if got.RunID != 31 || got.Status != "success" || got.Conclusion != "success" {
+		t.Errorf("RunID/Status/Conclusion = %d/%q/%q, want 31/success/success", got.RunID, got.Status, got.Conclusion)
+	}
+	if got.HeadBranch != "master" {
+		t.Errorf("HeadBranch = %q, want master", got.HeadBranch)
+	}
+}
+
+// releaseHookPayloadExample is GitLab's own documented example
+// payload for a Release Hook.
+const releaseHookPayloadExample = `{
+  "object_kind": "release",
+  "name": "v1.1",
+  "description": "v1.1 has been released",
+  "tag": "v1.1",
+  "url": "http://example.com/gitlab-org/release-webhook-example/-/releases/v1.1",
+  "action": "create",
+  "project": {"id": 2, "name": "release-webhook-example", "path_with_namespace": "gitlab-org/release-webhook-example"}
+}`
+
+func TestWebhookServiceParseReleaseHook(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(releaseHookPayloadExample))
+	req.Header.Set("X-Gitlab-Event", "Release Hook")
+
+	s := new(webhookService)
+	hook, err := s.Parse(req, func(scm.Webhook) (string, error) { return "", nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, ok := hook.(*scm.ReleaseHook)
+	if !ok {
+		t.Fatalf("Parse returned %T, want *scm.ReleaseHook", hook)
+	}
+	if got.Action != scm.ActionCreate {
+		t.Errorf("Action = %v, want scm.ActionCreate", got.Action)
+	}
+	if got.Release.Tag != "v1.1" || got.Release.Name != "v1.1" {
+		t.Errorf("Release = %#v, want Tag/Name v1.1", got.Release)
+	}
+	if got.Repo.FullName != "gitlab-org/release-webhook-example" {
+		t.Errorf("Repo.FullName = %q, want gitlab-org/release-webhook-example", got.Repo.FullName)
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright 2017 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scm
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type (
+	// SystemHook defines an instance- or group-level webhook,
+	// eg a GitLab System Hook or Group Hook. Unlike Webhook, a
+	// SystemHook is not necessarily scoped to a single
+	// Repository, so it exposes Kind alongside Repository.
+	SystemHook interface {
+		// Kind returns the event name, eg "project_create" or
+		// "user_destroy".
+		Kind() string
+
+		// Repository returns the associated repository, or the
+		// zero value Repository if this event isn't scoped to
+		// one, eg a UserSystemHook. This lets a SystemHook also
+		// satisfy Webhook, so it can flow through the same
+		// WebhookService.Parse return type as project-scoped
+		// hooks; callers that care should check Kind first.
+		Repository() Repository
+	}
+
+	// SystemHookUnmarshaler wraps SystemHook and assigns a type
+	// for unmarshalling. Use this if you need to deserialize
+	// SystemHooks of unknown concrete type.
+	SystemHookUnmarshaler struct {
+		Type       string
+		SystemHook SystemHook
+	}
+
+	// ProjectSystemHook represents a project lifecycle event at
+	// the instance or group level, eg project_create,
+	// project_destroy, project_rename and project_transfer.
+	ProjectSystemHook struct {
+		Event             string
+		ProjectID         int
+		Name              string
+		Path              string
+		PathWithNamespace string
+		OwnerName         string
+		OwnerEmail        string
+	}
+
+	// UserSystemHook represents a user lifecycle event at the
+	// instance level, eg user_create and user_destroy.
+	UserSystemHook struct {
+		Event    string
+		UserID   int
+		Name     string
+		Email    string
+		Username string
+	}
+
+	// GroupSystemHook represents a group lifecycle event at the
+	// instance level, eg group_create and group_destroy.
+	GroupSystemHook struct {
+		Event   string
+		GroupID int
+		Name    string
+		Path    string
+	}
+
+	// TeamMemberSystemHook represents a user being added to or
+	// removed from a group, eg user_add_to_team and
+	// user_remove_from_team.
+	TeamMemberSystemHook struct {
+		Event        string
+		UserID       int
+		UserUsername string
+		GroupID      int
+		GroupName    string
+		GroupAccess  string
+	}
+
+	// SSHKeySystemHook represents an SSH key being added to or
+	// removed from a user, eg key_create and key_destroy.
+	SSHKeySystemHook struct {
+		Event    string
+		ID       int
+		Username string
+		Key      string
+	}
+)
+
+func (h *ProjectSystemHook) Kind() string    { return h.Event }
+func (h *UserSystemHook) Kind() string       { return h.Event }
+func (h *GroupSystemHook) Kind() string      { return h.Event }
+func (h *TeamMemberSystemHook) Kind() string { return h.Event }
+func (h *SSHKeySystemHook) Kind() string     { return h.Event }
+
+// Repository returns a Repository built from the project fields
+// carried on the hook. ProjectSystemHook is the only SystemHook
+// scoped to a single repository; the rest return the zero value.
+func (h *ProjectSystemHook) Repository() Repository {
+	return Repository{
+		Name:      h.Name,
+		FullName:  h.PathWithNamespace,
+		Namespace: h.OwnerName,
+	}
+}
+
+func (h *UserSystemHook) Repository() Repository       { return Repository{} }
+func (h *GroupSystemHook) Repository() Repository      { return Repository{} }
+func (h *TeamMemberSystemHook) Repository() Repository { return Repository{} }
+func (h *SSHKeySystemHook) Repository() Repository     { return Repository{} }
+
+// MarshalJSON implements custom JSON marshaling logic.
+func (h *ProjectSystemHook) MarshalJSON() ([]byte, error) {
+	hook := make(map[string]interface{})
+	hook["type"] = "projectSystemHook"
+
+	hook["event"] = h.Event
+	hook["projectId"] = h.ProjectID
+	hook["name"] = h.Name
+	hook["path"] = h.Path
+	hook["pathWithNamespace"] = h.PathWithNamespace
+	hook["ownerName"] = h.OwnerName
+	hook["ownerEmail"] = h.OwnerEmail
+
+	return json.Marshal(hook)
+}
+
+// MarshalJSON implements custom JSON marshaling logic.
+func (h *UserSystemHook) MarshalJSON() ([]byte, error) {
+	hook := make(map[string]interface{})
+	hook["type"] = "userSystemHook"
+
+	hook["event"] = h.Event
+	hook["userId"] = h.UserID
+	hook["name"] = h.Name
+	hook["email"] = h.Email
+	hook["username"] = h.Username
+
+	return json.Marshal(hook)
+}
+
+// MarshalJSON implements custom JSON marshaling logic.
+func (h *GroupSystemHook) MarshalJSON() ([]byte, error) {
+	hook := make(map[string]interface{})
+	hook["type"] = "groupSystemHook"
+
+	hook["event"] = h.Event
+	hook["groupId"] = h.GroupID
+	hook["name"] = h.Name
+	hook["path"] = h.Path
+
+	return json.Marshal(hook)
+}
+
+// MarshalJSON implements custom JSON marshaling logic.
+func (h *TeamMemberSystemHook) MarshalJSON() ([]byte, error) {
+	hook := make(map[string]interface{})
+	hook["type"] = "teamMemberSystemHook"
+
+	hook["event"] = h.Event
+	hook["userId"] = h.UserID
+	hook["userUsername"] = h.UserUsername
+	hook["groupId"] = h.GroupID
+	hook["groupName"] = h.GroupName
+	hook["groupAccess"] = h.GroupAccess
+
+	return json.Marshal(hook)
+}
+
+// MarshalJSON implements custom JSON marshaling logic.
+func (h *SSHKeySystemHook) MarshalJSON() ([]byte, error) {
+	hook := make(map[string]interface{})
+	hook["type"] = "sshKeySystemHook"
+
+	hook["event"] = h.Event
+	hook["id"] = h.ID
+	hook["username"] = h.Username
+	hook["key"] = h.Key
+
+	return json.Marshal(hook)
+}
+
+// systemHookTypes maps the "type" discriminator written by each
+// SystemHook's MarshalJSON to a factory for its concrete type.
+var systemHookTypes = map[string]func() SystemHook{}
+
+// RegisterSystemHookType associates name, the "type"
+// discriminator a SystemHook's MarshalJSON writes, with a
+// factory that returns a new zero-value instance of its
+// concrete type.
+func RegisterSystemHookType(name string, factory func() SystemHook) {
+	systemHookTypes[name] = factory
+}
+
+func init() {
+	RegisterSystemHookType("projectSystemHook", func() SystemHook { return new(ProjectSystemHook) })
+	RegisterSystemHookType("userSystemHook", func() SystemHook { return new(UserSystemHook) })
+	RegisterSystemHookType("groupSystemHook", func() SystemHook { return new(GroupSystemHook) })
+	RegisterSystemHookType("teamMemberSystemHook", func() SystemHook { return new(TeamMemberSystemHook) })
+	RegisterSystemHookType("sshKeySystemHook", func() SystemHook { return new(SSHKeySystemHook) })
+}
+
+// UnmarshalJSON supports deserialization of a persisted
+// SystemHookUnmarshaler into a concrete implementation of
+// scm.SystemHook.
+func (su *SystemHookUnmarshaler) UnmarshalJSON(b []byte) error {
+	var envelope struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(b, &envelope); err != nil {
+		return err
+	}
+
+	su.Type = envelope.Type
+	factory, ok := systemHookTypes[su.Type]
+	if !ok {
+		return fmt.Errorf("scm: unknown system hook type %q", su.Type)
+	}
+
+	h := factory()
+	if err := json.Unmarshal(b, h); err != nil {
+		return err
+	}
+	su.SystemHook = h
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2017 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scm
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestSystemHookUnmarshalerRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		hook SystemHook
+	}{
+		{
+			name: "projectSystemHook",
+			hook: &ProjectSystemHook{Event: "project_create", ProjectID: 1, Name: "go-scm"},
+		},
+		{
+			name: "userSystemHook",
+			hook: &UserSystemHook{Event: "user_create", UserID: 1, Username: "octocat"},
+		},
+		{
+			name: "groupSystemHook",
+			hook: &GroupSystemHook{Event: "group_create", GroupID: 1, Name: "jenkins-x"},
+		},
+		{
+			name: "teamMemberSystemHook",
+			hook: &TeamMemberSystemHook{Event: "user_add_to_team", UserID: 1, GroupID: 1},
+		},
+		{
+			name: "sshKeySystemHook",
+			hook: &SSHKeySystemHook{Event: "key_create", ID: 1, Username: "octocat"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := json.Marshal(test.hook)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var su SystemHookUnmarshaler
+			if err := json.Unmarshal(data, &su); err != nil {
+				t.Fatal(err)
+			}
+
+			if su.Type != test.name {
+				t.Errorf("SystemHookUnmarshaler.Type = %q, want %q", su.Type, test.name)
+			}
+			if !reflect.DeepEqual(su.SystemHook, test.hook) {
+				t.Errorf("round-tripped system hook = %#v, want %#v", su.SystemHook, test.hook)
+			}
+		})
+	}
+}
+
+func TestSystemHookUnmarshalerUnknownType(t *testing.T) {
+	var su SystemHookUnmarshaler
+	err := json.Unmarshal([]byte(`{"type":"bogusSystemHook"}`), &su)
+	if err == nil {
+		t.Fatal("expected an error for an unregistered system hook type")
+	}
+}
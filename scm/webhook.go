@@ -7,7 +7,9 @@ package scm
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"time"
 )
 
 var (
@@ -157,6 +159,90 @@ type (
 		Task      string
 	}
 
+	// WorkflowDispatchHook represents a manually triggered
+	// workflow run, eg GitHub's workflow_dispatch event.
+	WorkflowDispatchHook struct {
+		Workflow string
+		Ref      string
+		Inputs   map[string]string
+		Repo     Repository
+		Sender   User
+	}
+
+	// WorkflowRunHook represents a change in the status of a
+	// workflow run, eg GitHub's workflow_run event.
+	WorkflowRunHook struct {
+		Action     Action
+		Workflow   string
+		RunID      int64
+		RunNumber  int
+		Status     string
+		Conclusion string
+		HeadBranch string
+		HeadSHA    string
+		Repo       Repository
+		Sender     User
+	}
+
+	// ReleaseAsset represents a single file attached to a
+	// Release.
+	ReleaseAsset struct {
+		Name string
+		Size int64
+		URL  string
+	}
+
+	// Release represents a tagged release of a repository.
+	Release struct {
+		Tag         string
+		Name        string
+		Body        string
+		Draft       bool
+		Prerelease  bool
+		Assets      []ReleaseAsset
+		HTMLURL     string
+		CreatedAt   time.Time
+		PublishedAt time.Time
+	}
+
+	// ReleaseHook represents a release lifecycle event, eg
+	// GitHub's release event.
+	ReleaseHook struct {
+		Action  Action
+		Release Release
+		Repo    Repository
+		Sender  User
+	}
+
+	// Package represents a package published to a repository's
+	// package registry.
+	Package struct {
+		Name     string
+		Version  string
+		Type     string
+		Registry string
+	}
+
+	// PackageHook represents a package publish event, eg
+	// GitHub's package event.
+	PackageHook struct {
+		Action  Action
+		Package Package
+		Repo    Repository
+		Sender  User
+	}
+
+	// DeploymentStatusHook represents an update to the status of
+	// a deployment created from a DeployHook.
+	DeploymentStatusHook struct {
+		DeployHook
+
+		State          string
+		LogURL         string
+		EnvironmentURL string
+		Description    string
+	}
+
 	// SecretFunc provides the Webhook parser with the
 	// secret key used to validate webhook authenticity.
 	SecretFunc func(webhook Webhook) (string, error)
@@ -182,6 +268,10 @@ func (h *IssueCommentHook) Repository() Repository       { return h.Repo }
 func (h *PullRequestHook) Repository() Repository        { return h.Repo }
 func (h *PullRequestCommentHook) Repository() Repository { return h.Repo }
 func (h *ReviewCommentHook) Repository() Repository      { return h.Repo }
+func (h *WorkflowDispatchHook) Repository() Repository   { return h.Repo }
+func (h *WorkflowRunHook) Repository() Repository        { return h.Repo }
+func (h *ReleaseHook) Repository() Repository            { return h.Repo }
+func (h *PackageHook) Repository() Repository            { return h.Repo }
 
 // MarshalJSON implements custom JSON marshaling logic.
 func (h *PushHook) MarshalJSON() ([]byte, error) {
@@ -318,103 +408,138 @@ func (h *ReviewCommentHook) MarshalJSON() ([]byte, error) {
 	return json.Marshal(hook)
 }
 
+// MarshalJSON implements custom JSON marshaling logic.
+func (h *WorkflowDispatchHook) MarshalJSON() ([]byte, error) {
+	hook := make(map[string]interface{})
+	hook["type"] = "workflowDispatchHook"
+
+	hook["workflow"] = h.Workflow
+	hook["ref"] = h.Ref
+	hook["inputs"] = h.Inputs
+	hook["repo"] = h.Repo
+	hook["sender"] = h.Sender
+
+	return json.Marshal(hook)
+}
+
+// MarshalJSON implements custom JSON marshaling logic.
+func (h *WorkflowRunHook) MarshalJSON() ([]byte, error) {
+	hook := make(map[string]interface{})
+	hook["type"] = "workflowRunHook"
+
+	hook["action"] = h.Action
+	hook["workflow"] = h.Workflow
+	hook["runId"] = h.RunID
+	hook["runNumber"] = h.RunNumber
+	hook["status"] = h.Status
+	hook["conclusion"] = h.Conclusion
+	hook["headBranch"] = h.HeadBranch
+	hook["headSha"] = h.HeadSHA
+	hook["repo"] = h.Repo
+	hook["sender"] = h.Sender
+
+	return json.Marshal(hook)
+}
+
+// MarshalJSON implements custom JSON marshaling logic.
+func (h *ReleaseHook) MarshalJSON() ([]byte, error) {
+	hook := make(map[string]interface{})
+	hook["type"] = "releaseHook"
+
+	hook["action"] = h.Action
+	hook["release"] = h.Release
+	hook["repo"] = h.Repo
+	hook["sender"] = h.Sender
+
+	return json.Marshal(hook)
+}
+
+// MarshalJSON implements custom JSON marshaling logic.
+func (h *PackageHook) MarshalJSON() ([]byte, error) {
+	hook := make(map[string]interface{})
+	hook["type"] = "packageHook"
+
+	hook["action"] = h.Action
+	hook["package"] = h.Package
+	hook["repo"] = h.Repo
+	hook["sender"] = h.Sender
+
+	return json.Marshal(hook)
+}
+
+// MarshalJSON implements custom JSON marshaling logic.
+func (h *DeploymentStatusHook) MarshalJSON() ([]byte, error) {
+	hook := make(map[string]interface{})
+	hook["type"] = "deploymentStatusHook"
+
+	hook["data"] = h.Data
+	hook["desc"] = h.Desc
+	hook["ref"] = h.Ref
+	hook["repo"] = h.Repo
+	hook["sender"] = h.Sender
+	hook["target"] = h.Target
+	hook["targetUrl"] = h.TargetURL
+	hook["task"] = h.Task
+	hook["state"] = h.State
+	hook["logUrl"] = h.LogURL
+	hook["environmentUrl"] = h.EnvironmentURL
+	hook["description"] = h.Description
+
+	return json.Marshal(hook)
+}
+
+// webhookTypes maps the "type" discriminator written by each
+// Webhook's MarshalJSON to a factory for its concrete type.
+// Built-in hook types register themselves below via init();
+// downstream consumers defining their own Webhook
+// implementations call RegisterWebhookType the same way.
+var webhookTypes = map[string]func() Webhook{}
+
+// RegisterWebhookType associates name, the "type" discriminator
+// a Webhook's MarshalJSON writes, with a factory that returns a
+// new zero-value instance of its concrete type. It is typically
+// called from an init function. Registering a name a second
+// time replaces the previous factory.
+func RegisterWebhookType(name string, factory func() Webhook) {
+	webhookTypes[name] = factory
+}
+
+func init() {
+	RegisterWebhookType("pushHook", func() Webhook { return new(PushHook) })
+	RegisterWebhookType("branchHook", func() Webhook { return new(BranchHook) })
+	RegisterWebhookType("deployHook", func() Webhook { return new(DeployHook) })
+	RegisterWebhookType("tagHook", func() Webhook { return new(TagHook) })
+	RegisterWebhookType("issueHook", func() Webhook { return new(IssueHook) })
+	RegisterWebhookType("issueCommentHook", func() Webhook { return new(IssueCommentHook) })
+	RegisterWebhookType("pullRequestHook", func() Webhook { return new(PullRequestHook) })
+	RegisterWebhookType("pullRequestCommentHook", func() Webhook { return new(PullRequestCommentHook) })
+	RegisterWebhookType("reviewCommentHook", func() Webhook { return new(ReviewCommentHook) })
+	RegisterWebhookType("workflowDispatchHook", func() Webhook { return new(WorkflowDispatchHook) })
+	RegisterWebhookType("workflowRunHook", func() Webhook { return new(WorkflowRunHook) })
+	RegisterWebhookType("releaseHook", func() Webhook { return new(ReleaseHook) })
+	RegisterWebhookType("packageHook", func() Webhook { return new(PackageHook) })
+	RegisterWebhookType("deploymentStatusHook", func() Webhook { return new(DeploymentStatusHook) })
+}
+
 // UnmarshalJSON supports deserialization of GitEventSpec.ParsedWebhook into a concrete implementation of scm.Webhook
 func (wu *WebhookUnmarshaler) UnmarshalJSON(b []byte) error {
-	var objMap map[string]*json.RawMessage
-	err := json.Unmarshal(b, &objMap)
-	if err != nil {
-		return err
+	var envelope struct {
+		Type string `json:"type"`
 	}
-
-	var rawMessage *json.RawMessage
-	var webhookMap map[string]string
-	err = json.Unmarshal(*rawMessage, &webhookMap)
-	if err != nil {
+	if err := json.Unmarshal(b, &envelope); err != nil {
 		return err
 	}
 
-	if webhookMap["type"] == "pushHook" {
-
-		var h *PushHook
-		err = json.Unmarshal(*rawMessage, h)
-		if err != nil {
-			return err
-		}
-		wu.Webhook = h
-
-	} else if webhookMap["type"] == "branchHook" {
-
-		var h *BranchHook
-		err = json.Unmarshal(*rawMessage, h)
-		if err != nil {
-			return err
-		}
-		wu.Webhook = h
-
-	} else if webhookMap["type"] == "deployHook" {
-
-		var h *DeployHook
-		err = json.Unmarshal(*rawMessage, h)
-		if err != nil {
-			return err
-		}
-		wu.Webhook = h
-
-	} else if webhookMap["type"] == "tagHook" {
-
-		var h *TagHook
-		err = json.Unmarshal(*rawMessage, h)
-		if err != nil {
-			return err
-		}
-		wu.Webhook = h
-
-	} else if webhookMap["type"] == "issueHook" {
-
-		var h *IssueHook
-		err = json.Unmarshal(*rawMessage, h)
-		if err != nil {
-			return err
-		}
-		wu.Webhook = h
-
-	} else if webhookMap["type"] == "issueCommentHook" {
-
-		var h *IssueHook
-		err = json.Unmarshal(*rawMessage, h)
-		if err != nil {
-			return err
-		}
-		wu.Webhook = h
-
-	} else if webhookMap["type"] == "pullRequestHook" {
-
-		var h *IssueHook
-		err = json.Unmarshal(*rawMessage, h)
-		if err != nil {
-			return err
-		}
-		wu.Webhook = h
-
-	} else if webhookMap["type"] == "pullRequestCommentHook" {
-
-		var h *IssueHook
-		err = json.Unmarshal(*rawMessage, h)
-		if err != nil {
-			return err
-		}
-		wu.Webhook = h
-
-	} else if webhookMap["type"] == "reviewCommentHook" {
-
-		var h *IssueHook
-		err = json.Unmarshal(*rawMessage, h)
-		if err != nil {
-			return err
-		}
-		wu.Webhook = h
-
+	wu.Type = envelope.Type
+	factory, ok := webhookTypes[wu.Type]
+	if !ok {
+		return fmt.Errorf("scm: unknown webhook type %q", wu.Type)
 	}
 
+	h := factory()
+	if err := json.Unmarshal(b, h); err != nil {
+		return err
+	}
+	wu.Webhook = h
 	return nil
 }
@@ -0,0 +1,187 @@
+// Copyright 2017 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package dispatcher forwards parsed scm.Webhook events to
+// third-party HTTP endpoints. Where scm.WebhookService only
+// parses inbound webhooks, a Dispatcher is the outbound
+// counterpart: it persists a delivery task, signs the request
+// the same way the originating forge would, and retries it in
+// the background until the target endpoint accepts it or
+// Config.MaxAttempts is reached.
+package dispatcher
+
+import (
+	"context"
+	"time"
+
+	"github.com/jenkins-x/go-scm/scm"
+)
+
+// TaskID uniquely identifies a queued delivery task.
+type TaskID string
+
+// Target describes the third-party endpoint a webhook should
+// be forwarded to.
+type Target struct {
+	URL    string
+	Method string
+	Header map[string][]string
+
+	// Secret is used to sign the outgoing request body the
+	// same way scm.WebhookService.Parse validates inbound
+	// signatures. It is never persisted to the TaskStore.
+	Secret string
+}
+
+// Task represents a single queued delivery of a webhook to a
+// Target. It is the unit of work persisted by a TaskStore.
+type Task struct {
+	ID             TaskID
+	HookType       string
+	TargetURL      string
+	Method         string
+	Header         map[string][]string
+	Body           []byte
+	ResponseStatus int
+	ResponseBody   []byte
+	IsDelivered    bool
+	IsSucceeded    bool
+	DeliveredAt    time.Time
+	PayloadVersion int
+	Attempts       int
+	CreatedAt      time.Time
+
+	// NotBefore is the earliest time this task is eligible for
+	// another delivery attempt. It backs off independently per
+	// task, so one task's retry delay never blocks delivery of
+	// any other task.
+	NotBefore time.Time
+}
+
+// TaskStore persists delivery tasks so that a Dispatcher can
+// survive a process restart without losing pending deliveries.
+// Implementations must be safe for concurrent use.
+type TaskStore interface {
+	// Create persists a new task and assigns it an ID.
+	Create(ctx context.Context, task *Task) error
+
+	// Update persists changes to an existing task, eg after a
+	// delivery attempt.
+	Update(ctx context.Context, task *Task) error
+
+	// Find returns the task with the given ID.
+	Find(ctx context.Context, id TaskID) (*Task, error)
+
+	// Pending returns up to limit tasks that have not yet
+	// been delivered (neither succeeded nor abandoned after
+	// MaxAttempts), are not before their NotBefore time, and are
+	// not already leased to another caller, oldest first. Each
+	// returned task is leased for the given duration so that a
+	// concurrent Pending call from another worker cannot claim
+	// it too; Update releases the lease early. Implementations
+	// must make the claim-and-return atomic so two callers can
+	// never be handed the same task.
+	Pending(ctx context.Context, limit int, lease time.Duration) ([]*Task, error)
+}
+
+// Callback is invoked as a task moves through its delivery
+// lifecycle.
+type Callback func(task *Task, err error)
+
+// Dispatcher enqueues scm.Webhook events for delivery to a
+// Target and drains the queue with a pool of background
+// workers.
+type Dispatcher interface {
+	// Enqueue persists the hook as a delivery task and returns
+	// its ID. Delivery itself happens asynchronously.
+	Enqueue(ctx context.Context, hook scm.Webhook, target Target) (TaskID, error)
+
+	// Start launches the worker pool. It blocks until ctx is
+	// canceled.
+	Start(ctx context.Context)
+}
+
+// Config controls retry and concurrency behavior of a
+// Dispatcher.
+type Config struct {
+	// Workers is the number of goroutines draining the queue.
+	// Defaults to 1.
+	Workers int
+
+	// MaxAttempts is the total number of delivery attempts,
+	// the first one included, made before a task is abandoned.
+	// Defaults to 10.
+	MaxAttempts int
+
+	// BaseBackoff is the delay before the first retry. Each
+	// subsequent retry doubles the delay, up to MaxBackoff.
+	// Defaults to 1s.
+	BaseBackoff time.Duration
+
+	// MaxBackoff caps the computed exponential delay. Defaults
+	// to 5m.
+	MaxBackoff time.Duration
+
+	// Jitter is the maximum random delay added to every retry
+	// to avoid thundering-herd retries across tasks. Defaults
+	// to 1s.
+	Jitter time.Duration
+
+	// RateLimit is the minimum delay between two deliveries to
+	// the same target URL. Zero disables per-target throttling.
+	// It is independent of a task's retry backoff: a task
+	// retrying after a failure still respects RateLimit, and a
+	// healthy task for a different target is never slowed down
+	// by another task's backoff.
+	RateLimit time.Duration
+
+	// PollInterval is how often idle workers check the
+	// TaskStore for new pending tasks. Defaults to 5s.
+	PollInterval time.Duration
+
+	// LeaseDuration bounds how long a task fetched by Pending
+	// stays claimed before another worker may retry it, in case
+	// the worker that fetched it dies mid-delivery. Defaults to
+	// 1m.
+	LeaseDuration time.Duration
+
+	// OnDelivered, when set, is called after every delivery
+	// attempt, successful or not.
+	OnDelivered Callback
+}
+
+func (c Config) withDefaults() Config {
+	if c.Workers <= 0 {
+		c.Workers = 1
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 10
+	}
+	if c.BaseBackoff <= 0 {
+		c.BaseBackoff = time.Second
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = 5 * time.Minute
+	}
+	if c.Jitter <= 0 {
+		c.Jitter = time.Second
+	}
+	if c.PollInterval <= 0 {
+		c.PollInterval = 5 * time.Second
+	}
+	if c.LeaseDuration <= 0 {
+		c.LeaseDuration = time.Minute
+	}
+	return c
+}
+
+// New returns a Dispatcher that persists tasks to store and
+// delivers them according to config.
+func New(store TaskStore, config Config) Dispatcher {
+	return &dispatcher{
+		store:  store,
+		config: config.withDefaults(),
+		rate:   map[string]time.Time{},
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2017 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dispatcher
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/go-scm/scm"
+)
+
+// TestDispatcherConcurrentWorkersDeliverOnce guards against two
+// worker goroutines both picking up the same pending task: with
+// Workers > 1 the target must see exactly one request per
+// Enqueue call, never a duplicate delivery.
+func TestDispatcherConcurrentWorkersDeliverOnce(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	store := NewMemoryStore()
+	d := New(store, Config{
+		Workers:      4,
+		PollInterval: 10 * time.Millisecond,
+	})
+
+	if _, err := d.Enqueue(context.Background(), &scm.PushHook{}, Target{URL: srv.URL}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	d.Start(ctx)
+
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Errorf("target received %d requests, want exactly 1", got)
+	}
+}
+
+// TestDispatcherBackoffIsPerTask ensures a failing task's retry
+// backoff does not delay delivery of an unrelated, healthy task
+// to the same target URL.
+func TestDispatcherBackoffIsPerTask(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&hits, 1)
+		if n == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	store := NewMemoryStore()
+	d := New(store, Config{
+		Workers:      1,
+		PollInterval: 10 * time.Millisecond,
+		BaseBackoff:  time.Hour, // would starve a second task if backoff were shared per-target
+		Jitter:       time.Millisecond,
+	})
+
+	failingID, err := d.Enqueue(context.Background(), &scm.PushHook{}, Target{URL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	healthyID, err := d.Enqueue(context.Background(), &scm.PushHook{}, Target{URL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	d.Start(ctx)
+
+	healthy, err := store.Find(context.Background(), healthyID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !healthy.IsSucceeded {
+		t.Errorf("healthy task did not succeed; an unrelated task's long backoff must not block it")
+	}
+
+	failing, err := store.Find(context.Background(), failingID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if failing.IsSucceeded || failing.NotBefore.IsZero() {
+		t.Errorf("failing task should have a non-zero NotBefore after its first failed attempt")
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2017 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dispatcher
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// newUUID returns a random RFC 4122 version 4 UUID. It exists
+// so this package has no dependency beyond the standard
+// library; TaskStore implementations backed by a database are
+// free to let the database generate the UUID instead.
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// NewMemoryStore returns a TaskStore that keeps tasks in
+// memory. It is suitable for tests and single-process
+// deployments; restart loses all pending deliveries.
+func NewMemoryStore() TaskStore {
+	return &memoryStore{
+		tasks:  map[TaskID]*Task{},
+		leases: map[TaskID]time.Time{},
+	}
+}
+
+type memoryStore struct {
+	mu     sync.Mutex
+	tasks  map[TaskID]*Task
+	leases map[TaskID]time.Time
+}
+
+func (s *memoryStore) Create(ctx context.Context, task *Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task.ID = TaskID(newUUID())
+	clone := *task
+	s.tasks[task.ID] = &clone
+	return nil
+}
+
+func (s *memoryStore) Update(ctx context.Context, task *Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.tasks[task.ID]; !ok {
+		return fmt.Errorf("dispatcher: task %s not found", task.ID)
+	}
+	clone := *task
+	s.tasks[task.ID] = &clone
+	delete(s.leases, task.ID)
+	return nil
+}
+
+func (s *memoryStore) Find(ctx context.Context, id TaskID) (*Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task, ok := s.tasks[id]
+	if !ok {
+		return nil, fmt.Errorf("dispatcher: task %s not found", id)
+	}
+	clone := *task
+	return &clone, nil
+}
+
+// Pending selects and leases eligible tasks atomically under a
+// single lock so that two concurrent callers — eg two worker
+// goroutines polling at once — can never be handed the same
+// task.
+func (s *memoryStore) Pending(ctx context.Context, limit int, lease time.Duration) ([]*Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var pending []*Task
+	for _, task := range s.tasks {
+		if task.IsSucceeded || task.IsDelivered {
+			continue
+		}
+		if task.NotBefore.After(now) {
+			continue
+		}
+		if until, leased := s.leases[task.ID]; leased && until.After(now) {
+			continue
+		}
+		pending = append(pending, task)
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].CreatedAt.Before(pending[j].CreatedAt)
+	})
+	if limit > 0 && len(pending) > limit {
+		pending = pending[:limit]
+	}
+
+	out := make([]*Task, len(pending))
+	for i, task := range pending {
+		s.leases[task.ID] = now.Add(lease)
+		clone := *task
+		out[i] = &clone
+	}
+	return out, nil
+}
@@ -0,0 +1,46 @@
+// Copyright 2017 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dispatcher
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+)
+
+// signSHA1 computes the X-Hub-Signature header value for body
+// using secret, as a "sha1=" prefixed hex HMAC. The GitLab
+// driver's webhookService.Parse does not check this header; it
+// compares X-Gitlab-Token instead.
+func signSHA1(secret string, body []byte) (string, error) {
+	sum, err := sign(sha1.New, secret, body)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("sha1=%s", sum), nil
+}
+
+// signSHA256 computes the X-Hub-Signature-256 header value for
+// body using secret, as a "sha256=" prefixed hex HMAC. The
+// GitLab driver's webhookService.Parse does not check this
+// header either.
+func signSHA256(secret string, body []byte) (string, error) {
+	sum, err := sign(sha256.New, secret, body)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("sha256=%s", sum), nil
+}
+
+func sign(newHash func() hash.Hash, secret string, body []byte) (string, error) {
+	mac := hmac.New(newHash, []byte(secret))
+	if _, err := mac.Write(body); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
@@ -0,0 +1,28 @@
+// Copyright 2017 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dispatcher
+
+// SQLSchema documents the table a SQL-backed TaskStore is
+// expected to use. It is not executed by this package; copy it
+// into your own migrations and implement TaskStore against it.
+const SQLSchema = `
+CREATE TABLE webhook_delivery_tasks (
+	uuid            VARCHAR(36)  NOT NULL PRIMARY KEY,
+	hook_type       VARCHAR(255) NOT NULL,
+	target_url      TEXT         NOT NULL,
+	method          VARCHAR(10)  NOT NULL,
+	headers         TEXT         NOT NULL,
+	request_body    BLOB         NOT NULL,
+	response_status INTEGER      NOT NULL DEFAULT 0,
+	response_body   BLOB,
+	is_delivered    BOOLEAN      NOT NULL DEFAULT FALSE,
+	is_succeeded    BOOLEAN      NOT NULL DEFAULT FALSE,
+	delivered_at    TIMESTAMP    NULL,
+	payload_version INTEGER      NOT NULL DEFAULT 1,
+	attempts        INTEGER      NOT NULL DEFAULT 0,
+	not_before      TIMESTAMP    NULL,
+	created_at      TIMESTAMP    NOT NULL
+);
+`
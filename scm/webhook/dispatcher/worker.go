@@ -0,0 +1,195 @@
+// Copyright 2017 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dispatcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jenkins-x/go-scm/scm"
+)
+
+// payloadVersion is bumped whenever the shape of a delivered
+// task body changes in a way downstream consumers should be
+// able to detect.
+const payloadVersion = 1
+
+// dispatcher is the default Dispatcher implementation. It
+// persists tasks to a TaskStore and drains them with a pool of
+// worker goroutines.
+type dispatcher struct {
+	store  TaskStore
+	config Config
+	client http.Client
+
+	mu   sync.Mutex
+	rate map[string]time.Time // targetURL -> earliest next attempt, from Config.RateLimit only
+}
+
+func (d *dispatcher) Enqueue(ctx context.Context, hook scm.Webhook, target Target) (TaskID, error) {
+	marshaler, ok := hook.(json.Marshaler)
+	if !ok {
+		return "", fmt.Errorf("dispatcher: hook type %T does not implement json.Marshaler", hook)
+	}
+	body, err := marshaler.MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+
+	header := http.Header{}
+	for k, vs := range target.Header {
+		for _, v := range vs {
+			header.Add(k, v)
+		}
+	}
+	header.Set("Content-Type", "application/json")
+	if target.Secret != "" {
+		sig, err := signSHA1(target.Secret, body)
+		if err != nil {
+			return "", err
+		}
+		sig256, err := signSHA256(target.Secret, body)
+		if err != nil {
+			return "", err
+		}
+		header.Set("X-Hub-Signature", sig)
+		header.Set("X-Hub-Signature-256", sig256)
+	}
+
+	method := target.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	task := &Task{
+		HookType:       fmt.Sprintf("%T", hook),
+		TargetURL:      target.URL,
+		Method:         method,
+		Header:         header,
+		Body:           body,
+		PayloadVersion: payloadVersion,
+		CreatedAt:      time.Now(),
+	}
+	if err := d.store.Create(ctx, task); err != nil {
+		return "", err
+	}
+	return task.ID, nil
+}
+
+// Start launches Config.Workers goroutines that poll the
+// TaskStore for pending tasks and deliver them, retrying with
+// exponential backoff until they succeed or exhaust
+// Config.MaxAttempts. Start blocks until ctx is canceled.
+func (d *dispatcher) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < d.config.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.run(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+func (d *dispatcher) run(ctx context.Context) {
+	ticker := time.NewTicker(d.config.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.drain(ctx)
+		}
+	}
+}
+
+func (d *dispatcher) drain(ctx context.Context) {
+	tasks, err := d.store.Pending(ctx, d.config.Workers, d.config.LeaseDuration)
+	if err != nil {
+		return
+	}
+	for _, task := range tasks {
+		if !d.acquire(task.TargetURL) {
+			continue
+		}
+		d.deliver(ctx, task)
+	}
+}
+
+// acquire reports whether targetURL is clear of the configured
+// Config.RateLimit, reserving the next allowed slot as a side
+// effect. It throttles a target regardless of which task is
+// being delivered to it, retrying or not; it adds nothing for a
+// task's own retry backoff, which is governed independently by
+// the task's NotBefore.
+func (d *dispatcher) acquire(targetURL string) bool {
+	if d.config.RateLimit <= 0 {
+		return true
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := time.Now()
+	if next, ok := d.rate[targetURL]; ok && now.Before(next) {
+		return false
+	}
+	d.rate[targetURL] = now.Add(d.config.RateLimit)
+	return true
+}
+
+func (d *dispatcher) deliver(ctx context.Context, task *Task) {
+	task.Attempts++
+
+	req, err := http.NewRequestWithContext(ctx, task.Method, task.TargetURL, bytes.NewReader(task.Body))
+	if err == nil {
+		req.Header = task.Header
+		var res *http.Response
+		res, err = d.client.Do(req)
+		if err == nil {
+			defer res.Body.Close()
+			task.ResponseStatus = res.StatusCode
+			task.ResponseBody, _ = ioutil.ReadAll(res.Body)
+			task.IsSucceeded = res.StatusCode >= 200 && res.StatusCode < 300
+		}
+	}
+
+	task.IsDelivered = task.IsSucceeded || task.Attempts >= d.config.MaxAttempts
+	switch {
+	case task.IsSucceeded || task.IsDelivered:
+		task.DeliveredAt = time.Now()
+	default:
+		// Back off this task alone; other tasks, including ones
+		// targeting the same URL, are unaffected.
+		delay := backoff(task.Attempts, d.config.BaseBackoff, d.config.MaxBackoff, d.config.Jitter)
+		task.NotBefore = time.Now().Add(delay)
+	}
+
+	if uerr := d.store.Update(ctx, task); uerr != nil && err == nil {
+		err = uerr
+	}
+
+	if d.config.OnDelivered != nil {
+		d.config.OnDelivered(task, err)
+	}
+}
+
+func backoff(attempt int, base, max, jitter time.Duration) time.Duration {
+	delay := base << uint(attempt-1)
+	if delay <= 0 || delay > max {
+		delay = max
+	}
+	if jitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(jitter)))
+	}
+	return delay
+}
@@ -0,0 +1,56 @@
+// Copyright 2017 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/jenkins-x/go-scm/scm"
+)
+
+func init() {
+	registerAll("dingtalk", dingtalkTransformer{})
+}
+
+// dingtalkTransformer renders a scm.Webhook as a DingTalk
+// custom robot markdown message.
+type dingtalkTransformer struct{}
+
+type dingtalkMessage struct {
+	MsgType  string           `json:"msgtype"`
+	Markdown dingtalkMarkdown `json:"markdown"`
+}
+
+type dingtalkMarkdown struct {
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
+
+func (dingtalkTransformer) Transform(hook scm.Webhook) (string, []byte, http.Header, error) {
+	s := summarize(hook)
+	text := fmt.Sprintf("#### %s\n", s.Title)
+	if s.Link != "" {
+		text += fmt.Sprintf("[%s](%s)\n", s.Repo, s.Link)
+	}
+	if s.Text != "" {
+		text += s.Text
+	}
+	msg := dingtalkMessage{
+		MsgType: "markdown",
+		Markdown: dingtalkMarkdown{
+			Title: s.Title,
+			Text:  text,
+		},
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	return "application/json", body, header, nil
+}
@@ -0,0 +1,49 @@
+// Copyright 2017 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/jenkins-x/go-scm/scm"
+)
+
+func init() {
+	registerAll("feishu", feishuTransformer{})
+}
+
+// feishuTransformer renders a scm.Webhook as a Feishu (Lark)
+// custom bot text message.
+type feishuTransformer struct{}
+
+type feishuMessage struct {
+	MsgType string        `json:"msg_type"`
+	Content feishuContent `json:"content"`
+}
+
+type feishuContent struct {
+	Text string `json:"text"`
+}
+
+func (feishuTransformer) Transform(hook scm.Webhook) (string, []byte, http.Header, error) {
+	s := summarize(hook)
+	text := s.Title
+	if s.Link != "" {
+		text = fmt.Sprintf("%s\n%s", text, s.Link)
+	}
+	msg := feishuMessage{
+		MsgType: "text",
+		Content: feishuContent{Text: text},
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	return "application/json", body, header, nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2017 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"fmt"
+
+	"github.com/jenkins-x/go-scm/scm"
+)
+
+// summary is the set of fields common to every hook type this
+// package renders. Each sink's Transformer builds its own body
+// from a summary so the wording stays consistent across sinks.
+type summary struct {
+	Title  string
+	Link   string
+	Text   string
+	Sender string
+	Repo   string
+}
+
+// summarize extracts a consistent title/URL/summary from hook
+// using its Repository(), Sender and action fields, so all
+// sinks share the same formatting logic instead of each
+// re-deriving it from the concrete hook type.
+func summarize(hook scm.Webhook) summary {
+	repo := hook.Repository()
+	s := summary{Repo: repo.FullName, Link: repo.Link}
+
+	switch h := hook.(type) {
+	case *scm.PushHook:
+		s.Sender = h.Sender.Login
+		s.Title = fmt.Sprintf("%s pushed to %s", s.Sender, h.Ref)
+		s.Text = fmt.Sprintf("%d commit(s)", len(h.Commits))
+		if h.Compare != "" {
+			s.Link = h.Compare
+		}
+	case *scm.PullRequestHook:
+		s.Sender = h.Sender.Login
+		s.Title = fmt.Sprintf("%s %s pull request #%d: %s", s.Sender, h.Action, h.PullRequest.Number, h.PullRequest.Title)
+		s.Text = h.PullRequest.Body
+		if h.PullRequest.Link != "" {
+			s.Link = h.PullRequest.Link
+		}
+	case *scm.IssueCommentHook:
+		s.Sender = h.Sender.Login
+		s.Title = fmt.Sprintf("%s commented on issue #%d: %s", s.Sender, h.Issue.Number, h.Issue.Title)
+		s.Text = h.Comment.Body
+		if h.Issue.Link != "" {
+			s.Link = h.Issue.Link
+		}
+	case *scm.ReviewCommentHook:
+		s.Title = fmt.Sprintf("%s on pull request #%d: %s", h.Action, h.PullRequest.Number, h.PullRequest.Title)
+		s.Text = h.Review.Body
+		if h.PullRequest.Link != "" {
+			s.Link = h.PullRequest.Link
+		}
+	case *scm.TagHook:
+		s.Sender = h.Sender.Login
+		s.Title = fmt.Sprintf("%s %s tag %s", s.Sender, h.Action, refName(h.Ref))
+	case *scm.BranchHook:
+		s.Sender = h.Sender.Login
+		s.Title = fmt.Sprintf("%s %s branch %s", s.Sender, h.Action, refName(h.Ref))
+	}
+	return s
+}
+
+// refName prefers the short reference name over its full path,
+// eg "main" over "refs/heads/main".
+func refName(ref scm.Reference) string {
+	if ref.Name != "" {
+		return ref.Name
+	}
+	return ref.Path
+}
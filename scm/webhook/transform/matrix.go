@@ -0,0 +1,47 @@
+// Copyright 2017 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/jenkins-x/go-scm/scm"
+)
+
+func init() {
+	registerAll("matrix", matrixTransformer{})
+}
+
+// matrixTransformer renders a scm.Webhook as an m.notice event
+// body, ready to PUT to a Matrix room's send endpoint.
+type matrixTransformer struct{}
+
+type matrixEvent struct {
+	MsgType       string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format,omitempty"`
+	FormattedBody string `json:"formatted_body,omitempty"`
+}
+
+func (matrixTransformer) Transform(hook scm.Webhook) (string, []byte, http.Header, error) {
+	s := summarize(hook)
+	event := matrixEvent{
+		MsgType: "m.notice",
+		Body:    s.Title,
+	}
+	if s.Link != "" {
+		event.Format = "org.matrix.custom.html"
+		event.FormattedBody = fmt.Sprintf(`<a href="%s">%s</a>`, s.Link, s.Title)
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	return "application/json", body, header, nil
+}
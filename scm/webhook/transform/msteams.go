@@ -0,0 +1,68 @@
+// Copyright 2017 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/jenkins-x/go-scm/scm"
+)
+
+func init() {
+	registerAll("msteams", msteamsTransformer{})
+}
+
+// msteamsTransformer renders a scm.Webhook as an MS Teams
+// MessageCard, posted to an incoming webhook connector URL.
+type msteamsTransformer struct{}
+
+type msteamsCard struct {
+	Type            string           `json:"@type"`
+	Context         string           `json:"@context"`
+	Summary         string           `json:"summary"`
+	Title           string           `json:"title"`
+	Text            string           `json:"text,omitempty"`
+	PotentialAction []msteamsOpenURL `json:"potentialAction,omitempty"`
+}
+
+type msteamsOpenURL struct {
+	Type    string          `json:"@type"`
+	Name    string          `json:"name"`
+	Targets []msteamsTarget `json:"targets"`
+}
+
+type msteamsTarget struct {
+	OS  string `json:"os"`
+	URI string `json:"uri"`
+}
+
+func (msteamsTransformer) Transform(hook scm.Webhook) (string, []byte, http.Header, error) {
+	s := summarize(hook)
+	card := msteamsCard{
+		Type:    "MessageCard",
+		Context: "http://schema.org/extensions",
+		Summary: s.Title,
+		Title:   s.Title,
+		Text:    s.Text,
+	}
+	if s.Link != "" {
+		card.PotentialAction = []msteamsOpenURL{{
+			Type: "OpenUri",
+			Name: "View",
+			Targets: []msteamsTarget{{
+				OS:  "default",
+				URI: s.Link,
+			}},
+		}}
+	}
+	body, err := json.Marshal(card)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	return "application/json", body, header, nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2017 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/jenkins-x/go-scm/scm"
+)
+
+func init() {
+	registerAll("slack", slackTransformer{})
+}
+
+// slackTransformer renders a scm.Webhook as the body of a
+// Slack incoming webhook request.
+type slackTransformer struct{}
+
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+func (slackTransformer) Transform(hook scm.Webhook) (string, []byte, http.Header, error) {
+	s := summarize(hook)
+	text := s.Title
+	if s.Link != "" {
+		text = fmt.Sprintf("<%s|%s>", s.Link, s.Title)
+	}
+	if s.Text != "" {
+		text = fmt.Sprintf("%s\n%s", text, s.Text)
+	}
+	body, err := json.Marshal(slackMessage{Text: text})
+	if err != nil {
+		return "", nil, nil, err
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	return "application/json", body, header, nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2017 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package transform converts a parsed scm.Webhook into the
+// vendor-specific body expected by popular chat sinks, so a
+// scm/webhook/dispatcher.Target can deliver forge events
+// straight into Slack, Discord, Matrix, MS Teams, DingTalk or
+// Feishu without each caller re-implementing the formatting.
+package transform
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/jenkins-x/go-scm/scm"
+)
+
+// Transformer converts a parsed scm.Webhook into the content
+// type, body and any extra headers required to POST it to a
+// chat sink.
+type Transformer interface {
+	Transform(hook scm.Webhook) (contentType string, body []byte, header http.Header, err error)
+}
+
+// hookType mirrors the "type" discriminator each scm.Webhook
+// implementation writes in MarshalJSON, eg "pushHook".
+func hookType(hook scm.Webhook) (string, error) {
+	switch hook.(type) {
+	case *scm.PushHook:
+		return "pushHook", nil
+	case *scm.PullRequestHook:
+		return "pullRequestHook", nil
+	case *scm.IssueCommentHook:
+		return "issueCommentHook", nil
+	case *scm.ReviewCommentHook:
+		return "reviewCommentHook", nil
+	case *scm.TagHook:
+		return "tagHook", nil
+	case *scm.BranchHook:
+		return "branchHook", nil
+	default:
+		return "", fmt.Errorf("transform: unsupported hook type %T", hook)
+	}
+}
+
+// registry maps a sink name (eg "slack") to the Transformer
+// registered for each hook type it supports.
+var registry = map[string]map[string]Transformer{}
+
+// Register associates a Transformer with a sink name and hook
+// type so it can later be retrieved with Lookup. Built-in
+// sinks register themselves from an init function; callers can
+// register additional sinks, or override a single hook type for
+// an existing sink, the same way.
+func Register(sink, hookType string, t Transformer) {
+	sinks, ok := registry[sink]
+	if !ok {
+		sinks = map[string]Transformer{}
+		registry[sink] = sinks
+	}
+	sinks[hookType] = t
+}
+
+// Lookup returns the Transformer registered for sink and the
+// concrete type of hook, if any.
+func Lookup(sink string, hook scm.Webhook) (Transformer, bool) {
+	kind, err := hookType(hook)
+	if err != nil {
+		return nil, false
+	}
+	t, ok := registry[sink][kind]
+	return t, ok
+}
+
+// registerAll registers t under sink for every hook type this
+// package knows how to summarize, the shape every built-in
+// Transformer needs since they all render from the same
+// summarize() helper.
+func registerAll(sink string, t Transformer) {
+	for _, kind := range []string{
+		"pushHook",
+		"pullRequestHook",
+		"issueCommentHook",
+		"reviewCommentHook",
+		"tagHook",
+		"branchHook",
+	} {
+		Register(sink, kind, t)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2017 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x/go-scm/scm"
+)
+
+func TestLookup(t *testing.T) {
+	push := &scm.PushHook{Repo: scm.Repository{FullName: "octocat/hello-world"}}
+	if _, ok := Lookup("slack", push); !ok {
+		t.Error("Lookup(slack, *scm.PushHook) = false, want true")
+	}
+	if _, ok := Lookup("unknown-sink", push); ok {
+		t.Error("Lookup(unknown-sink, *scm.PushHook) = true, want false")
+	}
+
+	unregistered := &scm.ReleaseHook{Repo: scm.Repository{FullName: "octocat/hello-world"}}
+	if _, ok := Lookup("slack", unregistered); ok {
+		t.Error("Lookup(slack, *scm.ReleaseHook) = true, want false: no sink registers ReleaseHook")
+	}
+}
+
+var pushHookFixture = &scm.PushHook{
+	Ref:  "refs/heads/main",
+	Repo: scm.Repository{FullName: "octocat/hello-world", Link: "https://example.com/octocat/hello-world"},
+	Sender: scm.User{
+		Login: "octocat",
+	},
+	Commits: []scm.PushCommit{{ID: "abc123"}},
+}
+
+var pullRequestHookFixture = &scm.PullRequestHook{
+	Action: scm.ActionOpen,
+	Repo:   scm.Repository{FullName: "octocat/hello-world"},
+	PullRequest: scm.PullRequest{
+		Number: 42,
+		Title:  "Add feature",
+		Link:   "https://example.com/octocat/hello-world/pull/42",
+	},
+	Sender: scm.User{Login: "octocat"},
+}
+
+func TestSinksTransformPushHook(t *testing.T) {
+	tests := []struct {
+		sink string
+		want []string
+	}{
+		{"slack", []string{"octocat pushed to refs/heads/main", "1 commit(s)"}},
+		{"discord", []string{"octocat pushed to refs/heads/main", "1 commit(s)"}},
+		{"matrix", []string{"octocat pushed to refs/heads/main"}},
+		{"msteams", []string{"octocat pushed to refs/heads/main", "1 commit(s)"}},
+		{"dingtalk", []string{"octocat pushed to refs/heads/main", "1 commit(s)"}},
+		{"feishu", []string{"octocat pushed to refs/heads/main"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sink, func(t *testing.T) {
+			tr, ok := Lookup(tt.sink, pushHookFixture)
+			if !ok {
+				t.Fatalf("Lookup(%s, *scm.PushHook) = false, want true", tt.sink)
+			}
+			contentType, body, header, err := tr.Transform(pushHookFixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if contentType != "application/json" {
+				t.Errorf("contentType = %q, want application/json", contentType)
+			}
+			if header.Get("Content-Type") != "application/json" {
+				t.Errorf("header Content-Type = %q, want application/json", header.Get("Content-Type"))
+			}
+			if !json.Valid(body) {
+				t.Fatalf("body is not valid JSON: %s", body)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(body), want) {
+					t.Errorf("body = %s, want it to contain %q", body, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSinksTransformPullRequestHook(t *testing.T) {
+	tests := []string{"slack", "discord", "matrix", "msteams", "dingtalk", "feishu"}
+
+	for _, sink := range tests {
+		t.Run(sink, func(t *testing.T) {
+			tr, ok := Lookup(sink, pullRequestHookFixture)
+			if !ok {
+				t.Fatalf("Lookup(%s, *scm.PullRequestHook) = false, want true", sink)
+			}
+			_, body, _, err := tr.Transform(pullRequestHookFixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !json.Valid(body) {
+				t.Fatalf("body is not valid JSON: %s", body)
+			}
+			if !strings.Contains(string(body), "pull request #42") {
+				t.Errorf("body = %s, want it to reference pull request #42", body)
+			}
+			if !strings.Contains(string(body), pullRequestHookFixture.PullRequest.Link) {
+				t.Errorf("body = %s, want it to contain the pull request link", body)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2017 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scm
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// TestWebhookUnmarshalerRoundTrip marshals every registered
+// hook type and deserializes it through WebhookUnmarshaler,
+// asserting the result is deeply equal to the original. This
+// guards against WebhookUnmarshaler.UnmarshalJSON dispatching
+// to the wrong concrete type for a given "type" discriminator.
+func TestWebhookUnmarshalerRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		hook Webhook
+	}{
+		{
+			name: "pushHook",
+			hook: &PushHook{Ref: "refs/heads/main", Repo: Repository{Name: "go-scm"}, After: "abc"},
+		},
+		{
+			name: "branchHook",
+			hook: &BranchHook{Ref: Reference{Name: "main"}, Repo: Repository{Name: "go-scm"}},
+		},
+		{
+			name: "deployHook",
+			hook: &DeployHook{Ref: Reference{Name: "main"}, Repo: Repository{Name: "go-scm"}, Task: "deploy"},
+		},
+		{
+			name: "tagHook",
+			hook: &TagHook{Ref: Reference{Name: "v1.0.0"}, Repo: Repository{Name: "go-scm"}},
+		},
+		{
+			name: "issueHook",
+			hook: &IssueHook{Repo: Repository{Name: "go-scm"}, Issue: Issue{Number: 1}},
+		},
+		{
+			name: "issueCommentHook",
+			hook: &IssueCommentHook{Repo: Repository{Name: "go-scm"}, Issue: Issue{Number: 1}},
+		},
+		{
+			name: "pullRequestHook",
+			hook: &PullRequestHook{Repo: Repository{Name: "go-scm"}, PullRequest: PullRequest{Number: 1}},
+		},
+		{
+			name: "pullRequestCommentHook",
+			hook: &PullRequestCommentHook{Repo: Repository{Name: "go-scm"}, PullRequest: PullRequest{Number: 1}},
+		},
+		{
+			name: "reviewCommentHook",
+			hook: &ReviewCommentHook{Repo: Repository{Name: "go-scm"}, PullRequest: PullRequest{Number: 1}},
+		},
+		{
+			name: "workflowDispatchHook",
+			hook: &WorkflowDispatchHook{Workflow: "ci.yml", Ref: "main", Repo: Repository{Name: "go-scm"}},
+		},
+		{
+			name: "workflowRunHook",
+			hook: &WorkflowRunHook{Workflow: "ci.yml", RunID: 42, Repo: Repository{Name: "go-scm"}},
+		},
+		{
+			name: "releaseHook",
+			hook: &ReleaseHook{Release: Release{Tag: "v1.0.0", Name: "v1.0.0"}, Repo: Repository{Name: "go-scm"}},
+		},
+		{
+			name: "packageHook",
+			hook: &PackageHook{Package: Package{Name: "go-scm", Version: "v1.0.0", Type: "npm"}, Repo: Repository{Name: "go-scm"}},
+		},
+		{
+			name: "deploymentStatusHook",
+			hook: &DeploymentStatusHook{
+				DeployHook: DeployHook{Repo: Repository{Name: "go-scm"}, Task: "deploy"},
+				State:      "success",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := json.Marshal(test.hook)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var wu WebhookUnmarshaler
+			if err := json.Unmarshal(data, &wu); err != nil {
+				t.Fatal(err)
+			}
+
+			if wu.Type != test.name {
+				t.Errorf("WebhookUnmarshaler.Type = %q, want %q", wu.Type, test.name)
+			}
+			if !reflect.DeepEqual(wu.Webhook, test.hook) {
+				t.Errorf("round-tripped hook = %#v, want %#v", wu.Webhook, test.hook)
+			}
+		})
+	}
+}
+
+func TestWebhookUnmarshalerUnknownType(t *testing.T) {
+	var wu WebhookUnmarshaler
+	err := json.Unmarshal([]byte(`{"type":"bogusHook"}`), &wu)
+	if err == nil {
+		t.Fatal("expected an error for an unregistered webhook type")
+	}
+}